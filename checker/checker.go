@@ -0,0 +1,94 @@
+// Package checker implements one Checker per endpoint protocol (http, tcp,
+// icmp, dns, tls). Each returns a normalized store.StatusRecord so the rest
+// of the pipeline - history, metrics, incidents - doesn't need to know
+// which protocol produced it.
+package checker
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/thesanjaysubedi/uptime/monitor"
+	"github.com/thesanjaysubedi/uptime/store"
+)
+
+// Endpoint types recognized by ForType and Validate.
+const (
+	TypeHTTP = "http"
+	TypeTCP  = "tcp"
+	TypeICMP = "icmp"
+	TypeDNS  = "dns"
+	TypeTLS  = "tls"
+)
+
+// Checker performs a single check against an endpoint and returns a
+// normalized result plus a downtime record when the check indicates the
+// endpoint is down.
+type Checker interface {
+	Check(ctx context.Context, endpoint monitor.Endpoint) (store.StatusRecord, *store.DowntimeRecord)
+}
+
+// ForType returns the Checker registered for an endpoint's Type, defaulting
+// to the HTTP checker when Type is empty.
+func ForType(t string) (Checker, error) {
+	switch t {
+	case "", TypeHTTP:
+		return httpChecker{}, nil
+	case TypeTCP:
+		return tcpChecker{}, nil
+	case TypeICMP:
+		return icmpChecker{}, nil
+	case TypeDNS:
+		return dnsChecker{}, nil
+	case TypeTLS:
+		return tlsChecker{}, nil
+	default:
+		return nil, fmt.Errorf("checker: unknown endpoint type %q", t)
+	}
+}
+
+// Validate checks that an endpoint's type-specific fields are well-formed
+// before it's registered, so bad configuration is rejected at creation
+// time rather than surfacing as a confusing check failure later.
+func Validate(endpoint monitor.Endpoint) error {
+	switch endpoint.Type {
+	case "", TypeHTTP:
+		if endpoint.ExpectedStatusMin != 0 && endpoint.ExpectedStatusMax != 0 && endpoint.ExpectedStatusMin > endpoint.ExpectedStatusMax {
+			return fmt.Errorf("checker: expectedStatusMin must be <= expectedStatusMax")
+		}
+		if endpoint.URL == "" {
+			return fmt.Errorf("checker: http endpoint requires a url")
+		}
+	case TypeTCP:
+		if _, _, err := net.SplitHostPort(endpoint.URL); err != nil {
+			return fmt.Errorf("checker: tcp endpoint requires a host:port url: %w", err)
+		}
+	case TypeICMP:
+		if endpoint.URL == "" {
+			return fmt.Errorf("checker: icmp endpoint requires a host in url")
+		}
+		if endpoint.PingCount < 0 {
+			return fmt.Errorf("checker: pingCount must not be negative")
+		}
+	case TypeDNS:
+		if endpoint.URL == "" {
+			return fmt.Errorf("checker: dns endpoint requires a hostname in url")
+		}
+		switch endpoint.DNSRecordType {
+		case "", "A", "AAAA", "CNAME", "MX", "TXT", "NS":
+		default:
+			return fmt.Errorf("checker: unsupported dnsRecordType %q", endpoint.DNSRecordType)
+		}
+	case TypeTLS:
+		if endpoint.URL == "" {
+			return fmt.Errorf("checker: tls endpoint requires a host:port url")
+		}
+		if endpoint.TLSExpiryThresholdDays < 0 {
+			return fmt.Errorf("checker: tlsExpiryThresholdDays must not be negative")
+		}
+	default:
+		return fmt.Errorf("checker: unknown endpoint type %q", endpoint.Type)
+	}
+	return nil
+}
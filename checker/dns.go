@@ -0,0 +1,89 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/thesanjaysubedi/uptime/monitor"
+	"github.com/thesanjaysubedi/uptime/store"
+)
+
+// dnsChecker resolves the endpoint's hostname (in URL) and considers it up
+// if the lookup returns at least one answer and, when ExpectedAnswer is
+// set, one of the answers contains it.
+type dnsChecker struct{}
+
+func (dnsChecker) Check(ctx context.Context, endpoint monitor.Endpoint) (store.StatusRecord, *store.DowntimeRecord) {
+	start := time.Now()
+	status := store.StatusRecord{Timestamp: start}
+
+	var resolver net.Resolver
+	answers, err := lookup(ctx, &resolver, endpoint.DNSRecordType, endpoint.URL)
+	status.ResponseTime = time.Since(start).Seconds()
+	if err != nil {
+		status.IsUp = false
+		status.Error = err.Error()
+		return status, downtime(start, err.Error())
+	}
+	status.Detail = strings.Join(answers, ", ")
+
+	if endpoint.ExpectedAnswer != "" {
+		found := false
+		for _, a := range answers {
+			if strings.Contains(a, endpoint.ExpectedAnswer) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			status.IsUp = false
+			reason := fmt.Sprintf("no answer contained %q, got %v", endpoint.ExpectedAnswer, answers)
+			status.Error = reason
+			return status, downtime(start, reason)
+		}
+	}
+
+	status.IsUp = true
+	return status, nil
+}
+
+func lookup(ctx context.Context, resolver *net.Resolver, recordType, host string) ([]string, error) {
+	switch recordType {
+	case "", "A", "AAAA":
+		addrs, err := resolver.LookupHost(ctx, host)
+		return addrs, err
+	case "CNAME":
+		cname, err := resolver.LookupCNAME(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		return []string{cname}, nil
+	case "MX":
+		records, err := resolver.LookupMX(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		answers := make([]string, len(records))
+		for i, r := range records {
+			answers[i] = r.Host
+		}
+		return answers, nil
+	case "TXT":
+		return resolver.LookupTXT(ctx, host)
+	case "NS":
+		records, err := resolver.LookupNS(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		answers := make([]string, len(records))
+		for i, r := range records {
+			answers[i] = r.Host
+		}
+		return answers, nil
+	default:
+		return nil, fmt.Errorf("checker: unsupported dnsRecordType %q", recordType)
+	}
+}
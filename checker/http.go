@@ -0,0 +1,90 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/thesanjaysubedi/uptime/monitor"
+	"github.com/thesanjaysubedi/uptime/store"
+)
+
+// httpChecker issues a GET (or endpoint.Method) request and considers the
+// endpoint up when the response status falls inside
+// [ExpectedStatusMin, ExpectedStatusMax] (default: any status < 400) and,
+// if KeywordMatch is set, the response body contains it.
+type httpChecker struct{}
+
+func (httpChecker) Check(ctx context.Context, endpoint monitor.Endpoint) (store.StatusRecord, *store.DowntimeRecord) {
+	start := time.Now()
+	status := store.StatusRecord{Timestamp: start}
+
+	method := endpoint.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint.URL, nil)
+	if err != nil {
+		status.Error = err.Error()
+		return status, downtime(start, err.Error())
+	}
+	for key, value := range endpoint.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	status.ResponseTime = time.Since(start).Seconds()
+	if err != nil {
+		status.IsUp = false
+		status.Error = err.Error()
+		return status, downtime(start, err.Error())
+	}
+	defer resp.Body.Close()
+
+	status.StatusCode = resp.StatusCode
+	if !statusInRange(resp.StatusCode, endpoint.ExpectedStatusMin, endpoint.ExpectedStatusMax) {
+		status.IsUp = false
+		reason := fmt.Sprintf("HTTP Status %d", resp.StatusCode)
+		return status, downtime(start, reason)
+	}
+
+	if endpoint.KeywordMatch != "" {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			status.IsUp = false
+			status.Error = err.Error()
+			return status, downtime(start, err.Error())
+		}
+		if !strings.Contains(string(body), endpoint.KeywordMatch) {
+			status.IsUp = false
+			reason := fmt.Sprintf("response body missing keyword %q", endpoint.KeywordMatch)
+			status.Error = reason
+			return status, downtime(start, reason)
+		}
+	}
+
+	status.IsUp = true
+	return status, nil
+}
+
+// statusInRange reports whether code satisfies the endpoint's expected
+// status range. When both bounds are zero (the default), any status below
+// 400 is considered successful. A zero max with a non-zero min means "no
+// upper bound" rather than an unsatisfiable range.
+func statusInRange(code, min, max int) bool {
+	if min == 0 && max == 0 {
+		return code < 400
+	}
+	if max == 0 {
+		return code >= min
+	}
+	return code >= min && code <= max
+}
+
+func downtime(start time.Time, reason string) *store.DowntimeRecord {
+	return &store.DowntimeRecord{Timestamp: start, Duration: "ongoing", Reason: reason}
+}
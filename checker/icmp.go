@@ -0,0 +1,56 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/thesanjaysubedi/uptime/monitor"
+	"github.com/thesanjaysubedi/uptime/store"
+)
+
+// icmpChecker pings the endpoint's host using the system ping binary,
+// since sending raw ICMP echo requests from Go requires privileges this
+// process isn't expected to run with. PingCount echo requests are sent;
+// the endpoint is up if ping exits successfully before ctx's deadline.
+type icmpChecker struct{}
+
+func (icmpChecker) Check(ctx context.Context, endpoint monitor.Endpoint) (store.StatusRecord, *store.DowntimeRecord) {
+	start := time.Now()
+	status := store.StatusRecord{Timestamp: start}
+
+	count := endpoint.PingCount
+	if count <= 0 {
+		count = 1
+	}
+
+	cmd := exec.CommandContext(ctx, "ping", "-c", strconv.Itoa(count), endpoint.URL)
+	output, err := cmd.CombinedOutput()
+	status.ResponseTime = time.Since(start).Seconds()
+	status.Detail = summaryLine(string(output))
+
+	if err != nil {
+		status.IsUp = false
+		reason := fmt.Sprintf("ping failed: %v", err)
+		status.Error = reason
+		return status, downtime(start, reason)
+	}
+
+	status.IsUp = true
+	return status, nil
+}
+
+// summaryLine returns the last non-empty line of ping's output, which is
+// typically the round-trip statistics summary.
+func summaryLine(output string) string {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if strings.TrimSpace(lines[i]) != "" {
+			return strings.TrimSpace(lines[i])
+		}
+	}
+	return ""
+}
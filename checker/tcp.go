@@ -0,0 +1,32 @@
+package checker
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/thesanjaysubedi/uptime/monitor"
+	"github.com/thesanjaysubedi/uptime/store"
+)
+
+// tcpChecker considers an endpoint up if a TCP connection to its
+// host:port URL can be established before ctx's deadline.
+type tcpChecker struct{}
+
+func (tcpChecker) Check(ctx context.Context, endpoint monitor.Endpoint) (store.StatusRecord, *store.DowntimeRecord) {
+	start := time.Now()
+	status := store.StatusRecord{Timestamp: start}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", endpoint.URL)
+	status.ResponseTime = time.Since(start).Seconds()
+	if err != nil {
+		status.IsUp = false
+		status.Error = err.Error()
+		return status, downtime(start, err.Error())
+	}
+	conn.Close()
+
+	status.IsUp = true
+	return status, nil
+}
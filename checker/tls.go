@@ -0,0 +1,63 @@
+package checker
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"time"
+
+	"github.com/thesanjaysubedi/uptime/monitor"
+	"github.com/thesanjaysubedi/uptime/store"
+)
+
+// defaultTLSExpiryThresholdDays is used when an endpoint doesn't set
+// TLSExpiryThresholdDays.
+const defaultTLSExpiryThresholdDays = 14
+
+// tlsChecker connects to the endpoint's host:port URL and treats a
+// certificate expiring within TLSExpiryThresholdDays as a soft failure,
+// even though the handshake itself succeeded.
+type tlsChecker struct{}
+
+func (tlsChecker) Check(ctx context.Context, endpoint monitor.Endpoint) (store.StatusRecord, *store.DowntimeRecord) {
+	start := time.Now()
+	status := store.StatusRecord{Timestamp: start}
+
+	threshold := endpoint.TLSExpiryThresholdDays
+	if threshold <= 0 {
+		threshold = defaultTLSExpiryThresholdDays
+	}
+
+	dialer := tls.Dialer{Config: &tls.Config{}}
+	rawConn, err := dialer.DialContext(ctx, "tcp", endpoint.URL)
+	status.ResponseTime = time.Since(start).Seconds()
+	if err != nil {
+		status.IsUp = false
+		status.Error = err.Error()
+		return status, downtime(start, err.Error())
+	}
+	conn := rawConn.(*tls.Conn)
+	defer conn.Close()
+
+	certs := conn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		reason := "no peer certificates presented"
+		status.IsUp = false
+		status.Error = reason
+		return status, downtime(start, reason)
+	}
+
+	expiry := certs[0].NotAfter
+	daysLeft := int(time.Until(expiry).Hours() / 24)
+	status.Detail = fmt.Sprintf("certificate expires in %d days (%s)", daysLeft, expiry.Format(time.RFC3339))
+
+	if daysLeft < threshold {
+		status.IsUp = false
+		reason := fmt.Sprintf("certificate expires in %d days, below threshold of %d", daysLeft, threshold)
+		status.Error = reason
+		return status, downtime(start, reason)
+	}
+
+	status.IsUp = true
+	return status, nil
+}
@@ -0,0 +1,32 @@
+package incident
+
+import (
+	"fmt"
+	"net/smtp"
+)
+
+// EmailNotifier sends a plain-text email through an SMTP relay when an
+// incident opens or resolves.
+type EmailNotifier struct {
+	Addr string // SMTP host:port
+	Auth smtp.Auth
+	From string
+	To   []string
+}
+
+// NewEmailNotifier returns an EmailNotifier that relays through addr.
+func NewEmailNotifier(addr, from string, to []string, auth smtp.Auth) *EmailNotifier {
+	return &EmailNotifier{Addr: addr, Auth: auth, From: from, To: to}
+}
+
+func (e *EmailNotifier) Notify(inc Incident) error {
+	subject := fmt.Sprintf("[uptime] %s %s", inc.Endpoint, stateVerb(inc.State))
+	body := fmt.Sprintf("Incident %s\nEndpoint: %s\nState: %s\nReason: %s\nStarted: %s\n",
+		inc.ID, inc.Endpoint, inc.State, inc.Reason, inc.StartedAt)
+	msg := []byte(fmt.Sprintf("Subject: %s\r\n\r\n%s", subject, body))
+
+	if err := smtp.SendMail(e.Addr, e.Auth, e.From, e.To, msg); err != nil {
+		return fmt.Errorf("incident: send email: %w", err)
+	}
+	return nil
+}
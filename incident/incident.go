@@ -0,0 +1,182 @@
+// Package incident collapses consecutive failing checks into first-class
+// Incidents and dispatches notifications through pluggable Notifiers when
+// one opens or resolves, replacing the ad-hoc "ongoing"-duration mutation
+// the monitor used to do inline.
+package incident
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/thesanjaysubedi/uptime/store"
+)
+
+// State is where an Incident sits in its lifecycle.
+type State string
+
+const (
+	StateOpen         State = "open"
+	StateAcknowledged State = "acknowledged"
+	StateResolved     State = "resolved"
+)
+
+// Incident is a span of consecutive failing checks for one endpoint.
+type Incident struct {
+	ID        string     `json:"id"`
+	Endpoint  string     `json:"endpoint"`
+	State     State      `json:"state"`
+	Reason    string     `json:"reason"`
+	StartedAt time.Time  `json:"startedAt"`
+	EndedAt   *time.Time `json:"endedAt,omitempty"`
+}
+
+// Manager tracks open incidents per endpoint and notifies registered
+// channels when one opens or resolves. A single failing check doesn't open
+// an incident by itself: Observe requires flapWindow consecutive failures
+// first, so a momentary blip doesn't page anyone.
+type Manager struct {
+	mu          sync.Mutex
+	nextID      int
+	open        map[string]*Incident // endpoint -> currently open incident
+	failStreak  map[string]int       // endpoint -> consecutive failing checks
+	streakStart map[string]time.Time // endpoint -> timestamp of the streak's first failure
+	all         []*Incident          // every incident ever opened, oldest first
+	channels    map[string]Notifier  // channel name -> notifier
+}
+
+// NewManager returns an empty Manager ready to use.
+func NewManager() *Manager {
+	return &Manager{
+		open:        make(map[string]*Incident),
+		failStreak:  make(map[string]int),
+		streakStart: make(map[string]time.Time),
+		channels:    make(map[string]Notifier),
+	}
+}
+
+// RegisterChannel makes a Notifier available under name for endpoints to
+// route to. Endpoints with no NotifyChannels of their own notify every
+// registered channel.
+func (m *Manager) RegisterChannel(name string, n Notifier) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.channels[name] = n
+}
+
+// Observe folds a single check result into the incident state machine for
+// endpoint. channels, when non-empty, restricts notification delivery to
+// those named channels; otherwise every registered channel is notified.
+func (m *Manager) Observe(endpoint string, check store.StatusRecord, flapWindow int, channels []string) {
+	if flapWindow <= 0 {
+		flapWindow = 1
+	}
+
+	m.mu.Lock()
+	if check.IsUp {
+		m.failStreak[endpoint] = 0
+		delete(m.streakStart, endpoint)
+		inc, ok := m.open[endpoint]
+		if !ok {
+			m.mu.Unlock()
+			return
+		}
+		endedAt := check.Timestamp
+		inc.State = StateResolved
+		inc.EndedAt = &endedAt
+		delete(m.open, endpoint)
+		snapshot := *inc
+		m.mu.Unlock()
+		m.notify(channels, snapshot)
+		return
+	}
+
+	if _, ok := m.open[endpoint]; ok {
+		// Already paged for this endpoint; wait for it to resolve.
+		m.mu.Unlock()
+		return
+	}
+
+	if m.failStreak[endpoint] == 0 {
+		m.streakStart[endpoint] = check.Timestamp
+	}
+	m.failStreak[endpoint]++
+	if m.failStreak[endpoint] < flapWindow {
+		m.mu.Unlock()
+		return
+	}
+
+	m.nextID++
+	inc := &Incident{
+		ID:        strconv.Itoa(m.nextID),
+		Endpoint:  endpoint,
+		State:     StateOpen,
+		Reason:    check.Error,
+		StartedAt: m.streakStart[endpoint],
+	}
+	m.open[endpoint] = inc
+	m.all = append(m.all, inc)
+	snapshot := *inc
+	m.mu.Unlock()
+	m.notify(channels, snapshot)
+}
+
+// List returns every incident, oldest first.
+func (m *Manager) List() []Incident {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out := make([]Incident, len(m.all))
+	for i, inc := range m.all {
+		out[i] = *inc
+	}
+	return out
+}
+
+// Acknowledge marks an open incident as acknowledged, silencing further
+// paging for it without waiting for it to resolve.
+func (m *Manager) Acknowledge(id string) (Incident, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, inc := range m.all {
+		if inc.ID == id {
+			if inc.State == StateOpen {
+				inc.State = StateAcknowledged
+			}
+			return *inc, nil
+		}
+	}
+	return Incident{}, fmt.Errorf("incident: no incident with id %q", id)
+}
+
+func (m *Manager) notify(channels []string, inc Incident) {
+	for _, n := range m.resolveChannels(channels) {
+		if err := n.Notify(inc); err != nil {
+			log.Printf("incident: notifier failed for %s: %v", inc.Endpoint, err)
+		}
+	}
+}
+
+func (m *Manager) resolveChannels(names []string) []Notifier {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(names) == 0 {
+		out := make([]Notifier, 0, len(m.channels))
+		for _, n := range m.channels {
+			out = append(out, n)
+		}
+		return out
+	}
+
+	out := make([]Notifier, 0, len(names))
+	for _, name := range names {
+		if n, ok := m.channels[name]; ok {
+			out = append(out, n)
+		}
+	}
+	return out
+}
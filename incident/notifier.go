@@ -0,0 +1,6 @@
+package incident
+
+// Notifier dispatches a notification when an incident opens or resolves.
+type Notifier interface {
+	Notify(incident Incident) error
+}
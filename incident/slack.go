@@ -0,0 +1,46 @@
+package incident
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// SlackNotifier posts a short incident summary to a Slack incoming webhook.
+type SlackNotifier struct {
+	WebhookURL string
+	Client     *http.Client
+}
+
+// NewSlackNotifier returns a SlackNotifier posting to webhookURL.
+func NewSlackNotifier(webhookURL string) *SlackNotifier {
+	return &SlackNotifier{WebhookURL: webhookURL, Client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (s *SlackNotifier) Notify(inc Incident) error {
+	text := fmt.Sprintf("[%s] %s %s: %s", inc.State, inc.Endpoint, stateVerb(inc.State), inc.Reason)
+	body, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.Client.Post(s.WebhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("incident: slack post: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("incident: slack webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func stateVerb(s State) string {
+	if s == StateResolved {
+		return "resolved"
+	}
+	return "is down"
+}
@@ -0,0 +1,38 @@
+package incident
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// WebhookNotifier POSTs the incident as JSON to a generic URL.
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+// NewWebhookNotifier returns a WebhookNotifier posting to url.
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{URL: url, Client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (w *WebhookNotifier) Notify(inc Incident) error {
+	body, err := json.Marshal(inc)
+	if err != nil {
+		return err
+	}
+
+	resp, err := w.Client.Post(w.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("incident: webhook post: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("incident: webhook %s returned status %d", w.URL, resp.StatusCode)
+	}
+	return nil
+}
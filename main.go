@@ -1,217 +1,413 @@
 package main
 
 import (
-    "encoding/json"
-    "fmt"
-    "log"
-    "net/http"
-    "sync"
-    "time"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/thesanjaysubedi/uptime/checker"
+	"github.com/thesanjaysubedi/uptime/incident"
+	"github.com/thesanjaysubedi/uptime/metrics"
+	"github.com/thesanjaysubedi/uptime/monitor"
+	"github.com/thesanjaysubedi/uptime/render"
+	"github.com/thesanjaysubedi/uptime/store"
 )
 
-// Endpoint represents a service endpoint to monitor
-type Endpoint struct {
-    Name string `json:"name"`
-    URL  string `json:"url"`
+// defaultWorkerPoolSize bounds how many endpoint checks run concurrently.
+const defaultWorkerPoolSize = 8
+
+var (
+	checkInterval = 30 * time.Minute
+	historyWindow = 10 * time.Hour
+
+	// registry tracks endpoints and their latest status, independent of the
+	// HTTP layer and the persistent history store.
+	registry = monitor.NewRegistry()
+
+	// historyStore persists every check so history survives restarts and
+	// can be queried beyond what's kept in memory.
+	historyStore store.HistoryStore
+
+	// checkPool fans endpoint checks out across a bounded set of workers,
+	// each guarded by a per-endpoint timeout and deadlock watchdog.
+	checkPool = monitor.NewPool(defaultWorkerPoolSize, performCheck, recordCheck)
+
+	// incidentManager collapses consecutive failing checks into incidents
+	// and pages through whichever notifier channels main registers.
+	incidentManager = incident.NewManager()
+
+	// statusRenderer writes the static status page after every check cycle
+	// when -output-dir is set; nil disables rendering entirely.
+	statusRenderer *render.Generator
+)
+
+func main() {
+	dbPath := flag.String("db", "uptime.db", "path to the BoltDB file used to persist check history")
+	webhookURL := flag.String("webhook-url", "", "generic webhook URL notified on incident open/resolve")
+	slackWebhookURL := flag.String("slack-webhook-url", "", "Slack incoming webhook URL notified on incident open/resolve")
+	outputDir := flag.String("output-dir", "", "directory to write the static status page (index.html, incidents.html) to after every check cycle; disabled if empty")
+	templateDir := flag.String("template-dir", "", "directory containing index.html.tmpl/incidents.html.tmpl overriding the built-in status page templates")
+	renderOnly := flag.Bool("render-only", false, "only run checks and render the static status page; don't start the HTTP server")
+	endpointsFile := flag.String("endpoints-file", "", "path to a JSON array of endpoints to register at startup; required for -render-only since no HTTP server is listening to accept POST /endpoint")
+	flag.Parse()
+
+	boltStore, err := store.NewBoltStore(*dbPath)
+	if err != nil {
+		log.Fatalf("failed to open history store: %v", err)
+	}
+	defer boltStore.Close()
+	historyStore = boltStore
+
+	if *endpointsFile != "" {
+		if err := loadEndpointsFile(*endpointsFile); err != nil {
+			log.Fatalf("failed to load -endpoints-file: %v", err)
+		}
+	}
+
+	if *webhookURL != "" {
+		incidentManager.RegisterChannel("webhook", incident.NewWebhookNotifier(*webhookURL))
+	}
+	if *slackWebhookURL != "" {
+		incidentManager.RegisterChannel("slack", incident.NewSlackNotifier(*slackWebhookURL))
+	}
+
+	if *outputDir != "" {
+		statusRenderer = render.NewGenerator(*outputDir, *templateDir)
+	}
+
+	if *renderOnly {
+		if statusRenderer == nil {
+			log.Fatal("-render-only requires -output-dir")
+		}
+		if len(registry.Endpoints()) == 0 {
+			log.Fatal("-render-only requires -endpoints-file since no HTTP server runs to populate endpoints via POST /endpoint")
+		}
+		fmt.Println("Running in render-only mode, no HTTP server will start...")
+		monitorEndpoints()
+		return
+	}
+
+	// API endpoints
+	http.HandleFunc("/endpoint", handleEndpoint)
+	http.HandleFunc("/status", getStatus)
+	http.HandleFunc("/api/history/day", historyHandler(24*time.Hour, 5*time.Minute))
+	http.HandleFunc("/api/history/week", historyHandler(7*24*time.Hour, time.Hour))
+	http.HandleFunc("/api/history/month", historyHandler(30*24*time.Hour, 24*time.Hour))
+	http.HandleFunc("/api/history/year", historyHandler(365*24*time.Hour, 7*24*time.Hour))
+	http.Handle("/metrics", metrics.Handler())
+	http.HandleFunc("/api/v1/query_range", queryRangeHandler)
+	http.HandleFunc("/incidents", handleIncidents)
+	http.HandleFunc("/incidents/", handleIncidentAck)
+
+	// Start the monitoring goroutine
+	go monitorEndpoints()
+
+	fmt.Println("Server starting on :8080...")
+	log.Fatal(http.ListenAndServe(":8080", nil))
 }
 
-// DowntimeRecord stores information about a downtime incident
-type DowntimeRecord struct {
-    Timestamp time.Time `json:"timestamp"`
-    Duration  string    `json:"duration"`
-    Reason    string    `json:"reason"`
+// handleIncidents lists every incident, oldest first.
+func handleIncidents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	json.NewEncoder(w).Encode(incidentManager.List())
 }
 
-// StatusRecord represents a single status check
-type StatusRecord struct {
-    Timestamp    time.Time `json:"timestamp"`
-    IsUp         bool      `json:"isUp"`
-    ResponseTime float64   `json:"responseTime"`
-    StatusCode   int       `json:"statusCode,omitempty"`
-    Error        string    `json:"error,omitempty"`
+// handleIncidentAck acknowledges the incident named by /incidents/{id}/ack.
+func handleIncidentAck(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/incidents/")
+	trimmed := strings.TrimSuffix(id, "/ack")
+	if trimmed == "" || trimmed == id {
+		http.NotFound(w, r)
+		return
+	}
+	id = trimmed
+
+	inc, err := incidentManager.Acknowledge(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	json.NewEncoder(w).Encode(inc)
 }
 
-// EndpointStatus represents the current and historical status of an endpoint
-type EndpointStatus struct {
-    Name           string           `json:"name"`
-    URL            string           `json:"url"`
-    CurrentStatus  string           `json:"currentStatus"`
-    LastChecked    time.Time        `json:"lastChecked"`
-    History        []StatusRecord   `json:"history"`
-    RecentDowntime []DowntimeRecord `json:"recentDowntime"`
+func handleEndpoint(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		var endpoint monitor.Endpoint
+		if err := json.NewDecoder(r.Body).Decode(&endpoint); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := checker.Validate(endpoint); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		registry.Add(endpoint)
+
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(endpoint)
+	}
 }
 
-var (
-    endpoints       = make(map[string]Endpoint)
-    endpointStatus = make(map[string]*EndpointStatus)
-    mu             sync.RWMutex
-    checkInterval  = 30 * time.Minute
-    historyWindow  = 10 * time.Hour
-)
+// loadEndpointsFile reads a JSON array of endpoints from path, validates
+// each the same way POST /endpoint does, and registers them. It's how
+// -render-only populates the registry in the absence of a running HTTP
+// server to accept POST /endpoint.
+func loadEndpointsFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
 
-func main() {
-    // API endpoints
-    http.HandleFunc("/endpoint", handleEndpoint)
-    http.HandleFunc("/status", getStatus)
-    
-    // Start the monitoring goroutine
-    go monitorEndpoints()
-    
-    fmt.Println("Server starting on :8080...")
-    log.Fatal(http.ListenAndServe(":8080", nil))
+	var endpoints []monitor.Endpoint
+	if err := json.Unmarshal(data, &endpoints); err != nil {
+		return fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	for _, endpoint := range endpoints {
+		if err := checker.Validate(endpoint); err != nil {
+			return fmt.Errorf("endpoint %q: %w", endpoint.Name, err)
+		}
+		registry.Add(endpoint)
+	}
+	return nil
 }
 
-func handleEndpoint(w http.ResponseWriter, r *http.Request) {
-    switch r.Method {
-    case http.MethodPost:
-        var endpoint Endpoint
-        if err := json.NewDecoder(r.Body).Decode(&endpoint); err != nil {
-            http.Error(w, err.Error(), http.StatusBadRequest)
-            return
-        }
-        
-        mu.Lock()
-        endpoints[endpoint.Name] = endpoint
-        endpointStatus[endpoint.Name] = &EndpointStatus{
-            Name:           endpoint.Name,
-            URL:            endpoint.URL,
-            History:        make([]StatusRecord, 0),
-            RecentDowntime: make([]DowntimeRecord, 0),
-        }
-        mu.Unlock()
-        
-        w.WriteHeader(http.StatusCreated)
-        json.NewEncoder(w).Encode(endpoint)
-    }
+// statusResponse is what /status returns per endpoint: the registry's
+// in-memory view plus history read through historyStore.
+type statusResponse struct {
+	monitor.Status
+	History []store.StatusRecord `json:"history"`
 }
 
+// getStatus returns the current status of every endpoint along with its
+// check history, read through historyStore. The window defaults to
+// historyWindow but can be widened with a "window" query parameter (e.g.
+// "?window=48h"), since retention is now a store concern, not a fixed
+// in-memory cap.
 func getStatus(w http.ResponseWriter, r *http.Request) {
-    mu.RLock()
-    defer mu.RUnlock()
-    
-    statusCopy := make(map[string]EndpointStatus)
-    for name, status := range endpointStatus {
-        // Filter history to last 10 hours
-        cutoff := time.Now().Add(-historyWindow)
-        filteredHistory := []StatusRecord{}
-        for _, record := range status.History {
-            if record.Timestamp.After(cutoff) {
-                filteredHistory = append(filteredHistory, record)
-            }
-        }
-        
-        // Copy status with limited history
-        statusCopy[name] = EndpointStatus{
-            Name:           status.Name,
-            URL:            status.URL,
-            CurrentStatus:  status.CurrentStatus,
-            LastChecked:    status.LastChecked,
-            History:        filteredHistory,
-            RecentDowntime: getLast5Downtimes(status.RecentDowntime),
-        }
-    }
-    
-    json.NewEncoder(w).Encode(statusCopy)
+	window := historyWindow
+	if raw := r.URL.Query().Get("window"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			http.Error(w, "invalid window: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		window = d
+	}
+	cutoff := time.Now().Add(-window)
+
+	snapshot := registry.Snapshot()
+	statusCopy := make(map[string]statusResponse, len(snapshot))
+	for name, status := range snapshot {
+		history, err := historyStore.StatusSince(name, cutoff)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		statusCopy[name] = statusResponse{Status: status, History: history}
+	}
+
+	json.NewEncoder(w).Encode(statusCopy)
 }
 
-func getLast5Downtimes(downtimes []DowntimeRecord) []DowntimeRecord {
-    if len(downtimes) <= 5 {
-        return downtimes
-    }
-    return downtimes[len(downtimes)-5:]
+// historyHandler builds a handler for one of the /api/history/* endpoints.
+// window is how far back the query reaches (e.g. 24h for the day view) and
+// defaultInterval is the bucket size used when the caller doesn't supply an
+// "interval" query parameter, given in minutes (e.g. "interval=5").
+func historyHandler(window time.Duration, defaultInterval time.Duration) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		serviceName := r.URL.Query().Get("service_name")
+		if serviceName == "" {
+			http.Error(w, "service_name is required", http.StatusBadRequest)
+			return
+		}
+
+		interval := defaultInterval
+		if raw := r.URL.Query().Get("interval"); raw != "" {
+			minutes, err := strconv.Atoi(raw)
+			if err != nil || minutes <= 0 {
+				http.Error(w, "interval must be a positive number of minutes", http.StatusBadRequest)
+				return
+			}
+			interval = time.Duration(minutes) * time.Minute
+		}
+
+		names := serviceNames(serviceName)
+
+		until := time.Now()
+		since := until.Add(-window)
+
+		result := make(map[string][]store.Bucket, len(names))
+		for _, name := range names {
+			statuses, err := historyStore.StatusSince(name, since)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			downtimes, err := historyStore.DowntimeSince(name, since)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			result[name] = store.Downsample(statuses, downtimes, since, until, interval)
+		}
+
+		json.NewEncoder(w).Encode(result)
+	}
 }
 
-func monitorEndpoints() {
-    ticker := time.NewTicker(checkInterval)
-    defer ticker.Stop()
-    
-    for {
-        checkAllEndpoints()
-        <-ticker.C
-    }
+// queryRangeHandler implements a Prometheus-compatible /api/v1/query_range
+// so existing Grafana dashboards can point at the uptime service directly.
+// It supports the "query" (metric name), "service_name" (or "all"),
+// "start", "end" and "step" parameters.
+func queryRangeHandler(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	metric := q.Get("query")
+	switch metric {
+	case store.MetricUp, store.MetricResponseSeconds:
+	case "":
+		http.Error(w, "query is required", http.StatusBadRequest)
+		return
+	default:
+		http.Error(w, fmt.Sprintf("unsupported metric %q", metric), http.StatusBadRequest)
+		return
+	}
+
+	start, err := parseTimeParam(q.Get("start"))
+	if err != nil {
+		http.Error(w, "invalid start: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	end, err := parseTimeParam(q.Get("end"))
+	if err != nil {
+		http.Error(w, "invalid end: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	step, err := parseStepParam(q.Get("step"))
+	if err != nil {
+		http.Error(w, "invalid step: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	names := serviceNames(q.Get("service_name"))
+
+	result := make([]store.Series, 0, len(names))
+	for _, name := range names {
+		records, err := historyStore.StatusSince(name, start)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		samples, err := store.RangeQuery(records, metric, start, end, step)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		result = append(result, store.Series{Labels: map[string]string{"name": name}, Samples: samples})
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": "success",
+		"data": map[string]interface{}{
+			"resultType": "matrix",
+			"result":     result,
+		},
+	})
 }
 
-func checkAllEndpoints() {
-    mu.RLock()
-    endpointsCopy := make(map[string]Endpoint)
-    for name, endpoint := range endpoints {
-        endpointsCopy[name] = endpoint
-    }
-    mu.RUnlock()
-    
-    for _, endpoint := range endpointsCopy {
-        checkEndpoint(endpoint)
-    }
+func parseTimeParam(raw string) (time.Time, error) {
+	if raw == "" {
+		return time.Time{}, fmt.Errorf("missing")
+	}
+	if sec, err := strconv.ParseFloat(raw, 64); err == nil {
+		return time.Unix(0, int64(sec*float64(time.Second))), nil
+	}
+	return time.Parse(time.RFC3339, raw)
 }
 
-func checkEndpoint(endpoint Endpoint) {
-    start := time.Now()
-    resp, err := http.Get(endpoint.URL)
-    responseTime := time.Since(start).Seconds()
-    
-    status := StatusRecord{
-        Timestamp:    time.Now(),
-        ResponseTime: responseTime,
-    }
-    
-    var downtime *DowntimeRecord
-    if err != nil {
-        status.IsUp = false
-        status.Error = err.Error()
-        downtime = &DowntimeRecord{
-            Timestamp: time.Now(),
-            Duration:  "ongoing",
-            Reason:    err.Error(),
-        }
-    } else {
-        status.IsUp = true
-        status.StatusCode = resp.StatusCode
-        if resp.StatusCode >= 400 {
-            status.IsUp = false
-            downtime = &DowntimeRecord{
-                Timestamp: time.Now(),
-                Duration:  "ongoing",
-                Reason:    fmt.Sprintf("HTTP Status %d", resp.StatusCode),
-            }
-        }
-        resp.Body.Close()
-    }
-    
-    mu.Lock()
-    if s, exists := endpointStatus[endpoint.Name]; exists {
-        s.LastChecked = time.Now()
-        s.CurrentStatus = getStatusString(status.IsUp)
-        s.History = append(s.History, status)
-        
-        // Add downtime record if service is down
-        if downtime != nil {
-            // Update duration of previous downtime if it exists
-            if len(s.RecentDowntime) > 0 {
-                lastDowntime := &s.RecentDowntime[len(s.RecentDowntime)-1]
-                if lastDowntime.Duration == "ongoing" {
-                    duration := time.Since(lastDowntime.Timestamp)
-                    lastDowntime.Duration = duration.String()
-                }
-            }
-            s.RecentDowntime = append(s.RecentDowntime, *downtime)
-        }
-        
-        // Cleanup old history (older than 10 hours)
-        cutoff := time.Now().Add(-historyWindow)
-        newHistory := []StatusRecord{}
-        for _, record := range s.History {
-            if record.Timestamp.After(cutoff) {
-                newHistory = append(newHistory, record)
-            }
-        }
-        s.History = newHistory
-    }
-    mu.Unlock()
+func parseStepParam(raw string) (time.Duration, error) {
+	if raw == "" {
+		return 0, fmt.Errorf("missing")
+	}
+	if d, err := time.ParseDuration(raw); err == nil {
+		return d, nil
+	}
+	if sec, err := strconv.ParseFloat(raw, 64); err == nil {
+		return time.Duration(sec * float64(time.Second)), nil
+	}
+	return 0, fmt.Errorf("invalid duration %q", raw)
 }
 
-func getStatusString(isUp bool) string {
-    if isUp {
-        return "UP"
-    }
-    return "DOWN"
-}
\ No newline at end of file
+// serviceNames expands the special "all" service name into every
+// registered endpoint name.
+func serviceNames(serviceName string) []string {
+	if serviceName == "all" || serviceName == "" {
+		return registry.Names()
+	}
+	return []string{serviceName}
+}
+
+func monitorEndpoints() {
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		checkPool.Run(registry.Endpoints())
+		if statusRenderer != nil {
+			if err := statusRenderer.Render(registry, historyStore, incidentManager); err != nil {
+				log.Printf("failed to render status page: %v", err)
+			}
+		}
+		<-ticker.C
+	}
+}
+
+// performCheck is the monitor.CheckFunc the pool uses to check a single
+// endpoint. It must respect ctx's deadline so the pool's watchdog can bound
+// how long it waits on a hung check, and it dispatches to the Checker
+// registered for the endpoint's Type.
+func performCheck(ctx context.Context, endpoint monitor.Endpoint) (store.StatusRecord, *store.DowntimeRecord) {
+	c, err := checker.ForType(endpoint.Type)
+	if err != nil {
+		now := time.Now()
+		return store.StatusRecord{Timestamp: now, IsUp: false, Error: err.Error()},
+			&store.DowntimeRecord{Timestamp: now, Duration: "ongoing", Reason: err.Error()}
+	}
+	return c.Check(ctx, endpoint)
+}
+
+// recordCheck is the monitor.ResultFunc the pool uses to hand back a
+// check's outcome, whether it completed normally or was declared
+// deadlocked by the watchdog.
+func recordCheck(endpoint monitor.Endpoint, status store.StatusRecord, downtime *store.DowntimeRecord) {
+	if err := historyStore.SaveStatus(endpoint.Name, status); err != nil {
+		log.Printf("failed to persist status for %s: %v", endpoint.Name, err)
+	}
+	if downtime != nil {
+		if err := historyStore.SaveDowntime(endpoint.Name, *downtime); err != nil {
+			log.Printf("failed to persist downtime for %s: %v", endpoint.Name, err)
+		}
+	}
+
+	metrics.Observe(endpoint.Name, status)
+	registry.Record(endpoint.Name, status, downtime)
+	incidentManager.Observe(endpoint.Name, status, endpoint.FlapWindow, endpoint.NotifyChannels)
+}
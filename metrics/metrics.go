@@ -0,0 +1,53 @@
+// Package metrics exposes collected checks in Prometheus exposition format
+// so existing Grafana/Prometheus dashboards can scrape the uptime service
+// directly instead of going through a sidecar exporter.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/thesanjaysubedi/uptime/store"
+)
+
+var (
+	up = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "uptime_endpoint_up",
+		Help: "Whether the last check of the endpoint succeeded (1) or not (0).",
+	}, []string{"name"})
+
+	responseSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "uptime_endpoint_response_seconds",
+		Help:    "Response time of endpoint checks, in seconds.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"name"})
+
+	failedChecksTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "uptime_endpoint_failed_checks_total",
+		Help: "Total number of failed checks per endpoint.",
+	}, []string{"name"})
+)
+
+func init() {
+	prometheus.MustRegister(up, responseSeconds, failedChecksTotal)
+}
+
+// Observe records the outcome of a single check against the Prometheus
+// collectors.
+func Observe(name string, record store.StatusRecord) {
+	if record.IsUp {
+		up.WithLabelValues(name).Set(1)
+	} else {
+		up.WithLabelValues(name).Set(0)
+		failedChecksTotal.WithLabelValues(name).Inc()
+	}
+	responseSeconds.WithLabelValues(name).Observe(record.ResponseTime)
+}
+
+// Handler returns the http.Handler that serves /metrics in the Prometheus
+// exposition format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
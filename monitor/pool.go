@@ -0,0 +1,188 @@
+package monitor
+
+import (
+	"context"
+	"log"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/thesanjaysubedi/uptime/store"
+)
+
+// Defaults applied when an Endpoint doesn't set the corresponding field.
+const (
+	DefaultTimeout            = 10 * time.Second
+	DefaultWatchdogMultiplier = 2.0
+	DefaultPoolSize           = 1
+)
+
+// CheckFunc performs a single check of an endpoint, respecting ctx's
+// deadline, and returns the resulting status plus a downtime record when
+// the check indicates the endpoint is down.
+type CheckFunc func(ctx context.Context, endpoint Endpoint) (store.StatusRecord, *store.DowntimeRecord)
+
+// ResultFunc receives the outcome of a check so the caller can persist it,
+// export it as a metric, and update the registry.
+type ResultFunc func(endpoint Endpoint, result store.StatusRecord, downtime *store.DowntimeRecord)
+
+// Pool fans checks for many endpoints out across a bounded number of
+// concurrent workers, so one hung endpoint can't stall checking every
+// other one. Each check also runs under a watchdog: if it's still running
+// past WatchdogMultiplier×Timeout, the pool gives up on it, records a
+// synthetic "check deadlocked" failure, and moves on rather than blocking.
+type Pool struct {
+	workers  int
+	check    CheckFunc
+	onResult ResultFunc
+
+	mu       sync.Mutex
+	inFlight map[string]chan struct{} // per-endpoint semaphore
+}
+
+// NewPool builds a Pool with the given number of concurrent workers. A
+// non-positive size falls back to runtime.GOMAXPROCS(0).
+func NewPool(workers int, check CheckFunc, onResult ResultFunc) *Pool {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	return &Pool{
+		workers:  workers,
+		check:    check,
+		onResult: onResult,
+		inFlight: make(map[string]chan struct{}),
+	}
+}
+
+// Run checks every endpoint once, fanning them out across the pool's
+// workers, and returns once all checks have completed or been declared
+// deadlocked.
+func (p *Pool) Run(endpoints []Endpoint) {
+	jobs := make(chan Endpoint, len(endpoints))
+	for _, e := range endpoints {
+		jobs <- e
+	}
+	close(jobs)
+
+	var wg sync.WaitGroup
+	for i := 0; i < p.workers; i++ {
+		wg.Add(1)
+		p.runSupervised(i, jobs, &wg)
+	}
+	wg.Wait()
+}
+
+// runSupervised starts a worker and, if it panics, logs a full stack dump
+// and restarts it, so a single bad check can't permanently shrink the pool.
+func (p *Pool) runSupervised(id int, jobs <-chan Endpoint, wg *sync.WaitGroup) {
+	go func() {
+		defer wg.Done()
+		for p.runWorker(id, jobs) {
+			log.Printf("monitor: worker %d restarting after panic", id)
+		}
+	}()
+}
+
+func (p *Pool) runWorker(id int, jobs <-chan Endpoint) (restart bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("monitor: worker %d panicked: %v\n%s", id, r, debug.Stack())
+			restart = true
+		}
+	}()
+	for endpoint := range jobs {
+		p.checkOne(endpoint)
+	}
+	return false
+}
+
+// checkOne runs a single endpoint's check under its configured timeout. If
+// the check is still running past the watchdog threshold, checkOne gives
+// up waiting on it, reports a deadlock, and returns, leaving the check
+// goroutine to finish (or not) on its own.
+func (p *Pool) checkOne(endpoint Endpoint) {
+	sem := p.semaphore(endpoint)
+	select {
+	case sem <- struct{}{}:
+	default:
+		log.Printf("monitor: skipping check of %q, already %d in flight", endpoint.Name, cap(sem))
+		return
+	}
+
+	timeout := endpoint.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	multiplier := endpoint.WatchdogMultiplier
+	if multiplier <= 0 {
+		multiplier = DefaultWatchdogMultiplier
+	}
+	watchdog := time.Duration(float64(timeout) * multiplier)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+
+	done := make(chan struct{})
+	var result store.StatusRecord
+	var downtime *store.DowntimeRecord
+	go func() {
+		defer close(done)
+		defer cancel()
+		defer func() { <-sem }()
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("monitor: check of %q panicked: %v\n%s", endpoint.Name, r, debug.Stack())
+				result = store.StatusRecord{
+					Timestamp: time.Now(),
+					IsUp:      false,
+					Error:     "check panicked",
+				}
+				downtime = &store.DowntimeRecord{
+					Timestamp: time.Now(),
+					Duration:  "ongoing",
+					Reason:    "check panicked",
+				}
+			}
+		}()
+		result, downtime = p.check(ctx, endpoint)
+	}()
+
+	select {
+	case <-done:
+		p.onResult(endpoint, result, downtime)
+	case <-time.After(watchdog):
+		log.Printf("monitor: check of %q exceeded watchdog threshold %s, treating as deadlocked\n%s",
+			endpoint.Name, watchdog, fullStackDump())
+		p.onResult(endpoint, store.StatusRecord{
+			Timestamp: time.Now(),
+			IsUp:      false,
+			Error:     "check deadlocked",
+		}, &store.DowntimeRecord{
+			Timestamp: time.Now(),
+			Duration:  "ongoing",
+			Reason:    "check deadlocked",
+		})
+	}
+}
+
+func (p *Pool) semaphore(endpoint Endpoint) chan struct{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	sem, ok := p.inFlight[endpoint.Name]
+	if !ok {
+		size := endpoint.PoolSize
+		if size <= 0 {
+			size = DefaultPoolSize
+		}
+		sem = make(chan struct{}, size)
+		p.inFlight[endpoint.Name] = sem
+	}
+	return sem
+}
+
+func fullStackDump() string {
+	buf := make([]byte, 1<<16)
+	n := runtime.Stack(buf, true)
+	return string(buf[:n])
+}
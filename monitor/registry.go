@@ -0,0 +1,184 @@
+// Package monitor tracks the set of endpoints under watch and their latest
+// status. It is deliberately ignorant of both HTTP and persistence so the
+// same Registry can back the status API, the Prometheus exporter, and the
+// check loop without any of them reaching into each other's state.
+package monitor
+
+import (
+	"sync"
+	"time"
+
+	"github.com/thesanjaysubedi/uptime/store"
+)
+
+// maxRecentDowntime bounds how many downtime records Registry keeps per
+// endpoint in memory; older ones are still available through the history
+// store.
+const maxRecentDowntime = 5
+
+// Endpoint represents a service endpoint to monitor.
+type Endpoint struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+
+	// Type selects which Checker implementation checks this endpoint:
+	// "http" (the default), "tcp", "icmp", "dns", or "tls". See the
+	// checker package for the fields each type reads below.
+	Type string `json:"type,omitempty"`
+
+	// HTTP-specific. Method defaults to GET; ExpectedStatusMin/Max bound
+	// the status codes treated as success (both zero means "< 400"); a
+	// non-empty KeywordMatch requires the response body to contain it.
+	Method            string            `json:"method,omitempty"`
+	Headers           map[string]string `json:"headers,omitempty"`
+	ExpectedStatusMin int               `json:"expectedStatusMin,omitempty"`
+	ExpectedStatusMax int               `json:"expectedStatusMax,omitempty"`
+	KeywordMatch      string            `json:"keywordMatch,omitempty"`
+
+	// ICMP-specific. PingCount is how many echo requests to send; it
+	// defaults to 1.
+	PingCount int `json:"pingCount,omitempty"`
+
+	// DNS-specific. DNSRecordType is one of A, AAAA, CNAME, MX, TXT, NS
+	// and defaults to A; ExpectedAnswer, if set, must appear among the
+	// resolved answers.
+	DNSRecordType  string `json:"dnsRecordType,omitempty"`
+	ExpectedAnswer string `json:"expectedAnswer,omitempty"`
+
+	// TLS-specific. TLSExpiryThresholdDays is the soft-failure threshold:
+	// the endpoint is reported down once the certificate has fewer days
+	// than this left before expiring. Defaults to 14.
+	TLSExpiryThresholdDays int `json:"tlsExpiryThresholdDays,omitempty"`
+
+	// Timeout bounds how long a single check of this endpoint may run
+	// before it's cancelled. Zero means DefaultTimeout.
+	Timeout time.Duration `json:"timeout,omitempty"`
+	// WatchdogMultiplier is how many multiples of Timeout a check may run
+	// past before the pool gives up waiting on it and declares it
+	// deadlocked rather than merely slow. Zero means
+	// DefaultWatchdogMultiplier.
+	WatchdogMultiplier float64 `json:"watchdogMultiplier,omitempty"`
+	// PoolSize caps how many checks of this endpoint may be in flight at
+	// once, so a chronically deadlocked endpoint can't accumulate an
+	// unbounded number of abandoned goroutines across check cycles. Zero
+	// means DefaultPoolSize.
+	PoolSize int `json:"poolSize,omitempty"`
+
+	// FlapWindow is how many consecutive failing checks must accumulate
+	// before an incident opens and notifications fire, so a single failed
+	// check doesn't page anyone. Zero means 1 (page immediately).
+	FlapWindow int `json:"flapWindow,omitempty"`
+	// NotifyChannels restricts incident notifications to these named
+	// channels (see incident.Manager.RegisterChannel). Empty notifies
+	// every registered channel.
+	NotifyChannels []string `json:"notifyChannels,omitempty"`
+}
+
+// Status is the current, in-memory view of an endpoint between checks.
+type Status struct {
+	Name           string                 `json:"name"`
+	URL            string                 `json:"url"`
+	CurrentStatus  string                 `json:"currentStatus"`
+	LastChecked    time.Time              `json:"lastChecked"`
+	RecentDowntime []store.DowntimeRecord `json:"recentDowntime"`
+}
+
+// Registry tracks the endpoints under watch and their latest status.
+type Registry struct {
+	mu        sync.RWMutex
+	endpoints map[string]Endpoint
+	statuses  map[string]*Status
+}
+
+// NewRegistry returns an empty Registry ready to use.
+func NewRegistry() *Registry {
+	return &Registry{
+		endpoints: make(map[string]Endpoint),
+		statuses:  make(map[string]*Status),
+	}
+}
+
+// Add registers a new endpoint to be monitored, resetting any status it
+// previously had.
+func (reg *Registry) Add(endpoint Endpoint) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	reg.endpoints[endpoint.Name] = endpoint
+	reg.statuses[endpoint.Name] = &Status{
+		Name:           endpoint.Name,
+		URL:            endpoint.URL,
+		RecentDowntime: make([]store.DowntimeRecord, 0),
+	}
+}
+
+// Endpoints returns a snapshot of every registered endpoint.
+func (reg *Registry) Endpoints() []Endpoint {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+
+	out := make([]Endpoint, 0, len(reg.endpoints))
+	for _, e := range reg.endpoints {
+		out = append(out, e)
+	}
+	return out
+}
+
+// Names returns the name of every registered endpoint.
+func (reg *Registry) Names() []string {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+
+	out := make([]string, 0, len(reg.endpoints))
+	for name := range reg.endpoints {
+		out = append(out, name)
+	}
+	return out
+}
+
+// Snapshot returns the current status of every registered endpoint, keyed
+// by name.
+func (reg *Registry) Snapshot() map[string]Status {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+
+	out := make(map[string]Status, len(reg.statuses))
+	for name, s := range reg.statuses {
+		out[name] = *s
+	}
+	return out
+}
+
+// Record updates an endpoint's current status after a check, folding in a
+// downtime record when the check failed.
+func (reg *Registry) Record(name string, check store.StatusRecord, downtime *store.DowntimeRecord) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	s, ok := reg.statuses[name]
+	if !ok {
+		return
+	}
+
+	s.LastChecked = check.Timestamp
+	if check.IsUp {
+		s.CurrentStatus = "UP"
+	} else {
+		s.CurrentStatus = "DOWN"
+	}
+
+	if downtime == nil {
+		return
+	}
+
+	if len(s.RecentDowntime) > 0 {
+		last := &s.RecentDowntime[len(s.RecentDowntime)-1]
+		if last.Duration == "ongoing" {
+			last.Duration = check.Timestamp.Sub(last.Timestamp).String()
+		}
+	}
+	s.RecentDowntime = append(s.RecentDowntime, *downtime)
+	if len(s.RecentDowntime) > maxRecentDowntime {
+		s.RecentDowntime = s.RecentDowntime[len(s.RecentDowntime)-maxRecentDowntime:]
+	}
+}
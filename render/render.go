@@ -0,0 +1,151 @@
+// Package render writes a self-contained static status page from the
+// current endpoint state and incident history, so operators can serve it
+// from nginx or S3 without exposing the Go server itself.
+package render
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/thesanjaysubedi/uptime/incident"
+	"github.com/thesanjaysubedi/uptime/monitor"
+	"github.com/thesanjaysubedi/uptime/store"
+)
+
+//go:embed templates/*.html.tmpl
+var defaultTemplates embed.FS
+
+// UptimeWindow is how far back the per-service uptime bar chart on
+// index.html looks.
+const UptimeWindow = 90 * 24 * time.Hour
+
+// Generator renders the static status page to OutputDir on demand.
+// TemplateDir, if set, overrides the built-in templates with files of the
+// same name (index.html.tmpl, incidents.html.tmpl) so operators can
+// customize branding.
+type Generator struct {
+	OutputDir   string
+	TemplateDir string
+}
+
+// NewGenerator returns a Generator writing to outputDir using the built-in
+// templates, or the ones in templateDir if it's non-empty.
+func NewGenerator(outputDir, templateDir string) *Generator {
+	return &Generator{OutputDir: outputDir, TemplateDir: templateDir}
+}
+
+// endpointView is what index.html.tmpl renders per endpoint.
+type endpointView struct {
+	monitor.Status
+	UptimeBars []uptimeBar
+}
+
+// uptimeBar is a single day's bar in the uptime chart.
+type uptimeBar struct {
+	Day       string
+	UptimePct float64
+}
+
+// Render writes index.html and incidents.html into g.OutputDir, reading
+// current status from registry and history from historyStore and
+// incidents.
+func (g *Generator) Render(registry *monitor.Registry, historyStore store.HistoryStore, incidents *incident.Manager) error {
+	if err := os.MkdirAll(g.OutputDir, 0o755); err != nil {
+		return fmt.Errorf("render: create output dir: %w", err)
+	}
+
+	if err := g.renderIndex(registry, historyStore); err != nil {
+		return err
+	}
+	return g.renderIncidents(incidents)
+}
+
+func (g *Generator) renderIndex(registry *monitor.Registry, historyStore store.HistoryStore) error {
+	tmpl, err := g.parse("index.html.tmpl")
+	if err != nil {
+		return err
+	}
+
+	snapshot := registry.Snapshot()
+	until := time.Now()
+	since := until.Add(-UptimeWindow)
+
+	views := make([]endpointView, 0, len(snapshot))
+	for _, status := range snapshot {
+		statuses, err := historyStore.StatusSince(status.Name, since)
+		if err != nil {
+			return fmt.Errorf("render: status history for %s: %w", status.Name, err)
+		}
+		downtimes, err := historyStore.DowntimeSince(status.Name, since)
+		if err != nil {
+			return fmt.Errorf("render: downtime history for %s: %w", status.Name, err)
+		}
+		buckets := store.Downsample(statuses, downtimes, since, until, 24*time.Hour)
+
+		bars := make([]uptimeBar, len(buckets))
+		for i, b := range buckets {
+			bars[i] = uptimeBar{Day: b.Start.Format("2006-01-02"), UptimePct: b.UptimePct}
+		}
+
+		views = append(views, endpointView{Status: status, UptimeBars: bars})
+	}
+	sort.Slice(views, func(i, j int) bool { return views[i].Name < views[j].Name })
+
+	return g.write(tmpl, "index.html", struct {
+		GeneratedAt time.Time
+		Endpoints   []endpointView
+	}{time.Now(), views})
+}
+
+func (g *Generator) renderIncidents(incidents *incident.Manager) error {
+	tmpl, err := g.parse("incidents.html.tmpl")
+	if err != nil {
+		return err
+	}
+
+	all := incidents.List()
+	sort.Slice(all, func(i, j int) bool { return all[i].StartedAt.After(all[j].StartedAt) })
+
+	return g.write(tmpl, "incidents.html", struct {
+		GeneratedAt time.Time
+		Incidents   []incident.Incident
+	}{time.Now(), all})
+}
+
+// parse loads name from g.TemplateDir if set, falling back to the built-in
+// template embedded at build time.
+func (g *Generator) parse(name string) (*template.Template, error) {
+	if g.TemplateDir != "" {
+		path := filepath.Join(g.TemplateDir, name)
+		tmpl, err := template.New(name).ParseFiles(path)
+		if err != nil {
+			return nil, fmt.Errorf("render: parse %s: %w", path, err)
+		}
+		return tmpl, nil
+	}
+
+	tmpl, err := template.New(name).ParseFS(defaultTemplates, "templates/"+name)
+	if err != nil {
+		return nil, fmt.Errorf("render: parse embedded %s: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+func (g *Generator) write(tmpl *template.Template, name string, data interface{}) error {
+	path := filepath.Join(g.OutputDir, name)
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("render: create %s: %w", path, err)
+	}
+	defer f.Close()
+
+	if err := tmpl.Execute(f, data); err != nil {
+		return fmt.Errorf("render: execute %s: %w", path, err)
+	}
+	return nil
+}
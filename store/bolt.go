@@ -0,0 +1,140 @@
+package store
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	bolt "github.com/boltdb/bolt"
+)
+
+var (
+	statusBucket   = []byte("status")
+	downtimeBucket = []byte("downtime")
+	servicesBucket = []byte("services")
+)
+
+// BoltStore is the default HistoryStore, backed by a single BoltDB file.
+// Each service gets its own nested bucket under statusBucket/downtimeBucket,
+// keyed by the record's timestamp (big-endian unix nanos) so range scans
+// come back in chronological order for free.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// NewBoltStore opens (creating if necessary) a BoltDB file at path and
+// prepares the top-level buckets used to index services.
+func NewBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("store: open bolt db: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{statusBucket, downtimeBucket, servicesBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("store: init buckets: %w", err)
+	}
+
+	return &BoltStore{db: db}, nil
+}
+
+func timeKey(t time.Time) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(t.UnixNano()))
+	return key
+}
+
+func (s *BoltStore) SaveStatus(service string, record StatusRecord) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(servicesBucket).Put([]byte(service), []byte{1}); err != nil {
+			return err
+		}
+		bucket, err := tx.Bucket(statusBucket).CreateBucketIfNotExists([]byte(service))
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		return bucket.Put(timeKey(record.Timestamp), data)
+	})
+}
+
+func (s *BoltStore) SaveDowntime(service string, record DowntimeRecord) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.Bucket(downtimeBucket).CreateBucketIfNotExists([]byte(service))
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		return bucket.Put(timeKey(record.Timestamp), data)
+	})
+}
+
+func (s *BoltStore) StatusSince(service string, since time.Time) ([]StatusRecord, error) {
+	var records []StatusRecord
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(statusBucket).Bucket([]byte(service))
+		if bucket == nil {
+			return nil
+		}
+		c := bucket.Cursor()
+		for k, v := c.Seek(timeKey(since)); k != nil; k, v = c.Next() {
+			var record StatusRecord
+			if err := json.Unmarshal(v, &record); err != nil {
+				return err
+			}
+			records = append(records, record)
+		}
+		return nil
+	})
+	return records, err
+}
+
+func (s *BoltStore) DowntimeSince(service string, since time.Time) ([]DowntimeRecord, error) {
+	var records []DowntimeRecord
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(downtimeBucket).Bucket([]byte(service))
+		if bucket == nil {
+			return nil
+		}
+		c := bucket.Cursor()
+		for k, v := c.Seek(timeKey(since)); k != nil; k, v = c.Next() {
+			var record DowntimeRecord
+			if err := json.Unmarshal(v, &record); err != nil {
+				return err
+			}
+			records = append(records, record)
+		}
+		return nil
+	})
+	return records, err
+}
+
+func (s *BoltStore) Services() ([]string, error) {
+	var names []string
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(servicesBucket).ForEach(func(k, v []byte) error {
+			names = append(names, string(k))
+			return nil
+		})
+	})
+	return names, err
+}
+
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,78 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Supported metric names for RangeQuery, matching the gauges/histograms
+// published on /metrics.
+const (
+	MetricUp              = "uptime_endpoint_up"
+	MetricResponseSeconds = "uptime_endpoint_response_seconds"
+)
+
+// Sample is a single timestamped value in a Series.
+type Sample struct {
+	Timestamp time.Time
+	Value     float64
+}
+
+// MarshalJSON encodes a Sample as a Prometheus-style [timestamp, "value"]
+// pair so existing /api/v1/query_range clients can parse it unmodified.
+func (s Sample) MarshalJSON() ([]byte, error) {
+	ts := float64(s.Timestamp.UnixNano()) / float64(time.Second)
+	return json.Marshal([2]interface{}{ts, strconv.FormatFloat(s.Value, 'f', -1, 64)})
+}
+
+// Series is a named, labeled sequence of samples, modeled after a
+// Prometheus range vector result.
+type Series struct {
+	Labels  map[string]string `json:"metric"`
+	Samples []Sample          `json:"values"`
+}
+
+// RangeQuery evaluates metric over records at each step between start and
+// end (inclusive), Prometheus-style: the value at each timestamp is the
+// most recent record at or before it. Steps with no preceding record are
+// omitted.
+func RangeQuery(records []StatusRecord, metric string, start, end time.Time, step time.Duration) ([]Sample, error) {
+	if step <= 0 {
+		return nil, fmt.Errorf("store: step must be positive")
+	}
+	if !end.After(start) {
+		return nil, fmt.Errorf("store: end must be after start")
+	}
+	switch metric {
+	case MetricUp, MetricResponseSeconds:
+	default:
+		return nil, fmt.Errorf("store: unsupported metric %q", metric)
+	}
+
+	valueAt := func(r StatusRecord) float64 {
+		switch metric {
+		case MetricUp:
+			if r.IsUp {
+				return 1
+			}
+			return 0
+		default: // MetricResponseSeconds
+			return r.ResponseTime
+		}
+	}
+
+	var samples []Sample
+	idx := 0
+	for t := start; !t.After(end); t = t.Add(step) {
+		for idx < len(records) && !records[idx].Timestamp.After(t) {
+			idx++
+		}
+		if idx == 0 {
+			continue
+		}
+		samples = append(samples, Sample{Timestamp: t, Value: valueAt(records[idx-1])})
+	}
+	return samples, nil
+}
@@ -0,0 +1,125 @@
+// Package store persists endpoint check history so it survives restarts
+// and can be queried over arbitrary time ranges instead of the fixed
+// in-memory window the server keeps for quick access.
+package store
+
+import (
+	"sort"
+	"time"
+)
+
+// StatusRecord represents a single status check. It's the normalized
+// result every Checker produces, regardless of which protocol it speaks:
+// StatusCode is only meaningful for HTTP checks, and Detail carries a
+// protocol-specific note (e.g. a resolved DNS answer or days until TLS
+// expiry) that doesn't warrant its own column.
+type StatusRecord struct {
+	Timestamp    time.Time `json:"timestamp"`
+	IsUp         bool      `json:"isUp"`
+	ResponseTime float64   `json:"responseTime"`
+	StatusCode   int       `json:"statusCode,omitempty"`
+	Error        string    `json:"error,omitempty"`
+	Detail       string    `json:"detail,omitempty"`
+}
+
+// DowntimeRecord stores information about a downtime incident.
+type DowntimeRecord struct {
+	Timestamp time.Time `json:"timestamp"`
+	Duration  string    `json:"duration"`
+	Reason    string    `json:"reason"`
+}
+
+// HistoryStore persists status checks and downtime incidents for every
+// monitored service and lets callers read them back by time range. The
+// default implementation is BoltStore, but the interface exists so other
+// backends (e.g. SQLite) can be swapped in without touching callers.
+type HistoryStore interface {
+	SaveStatus(service string, record StatusRecord) error
+	SaveDowntime(service string, record DowntimeRecord) error
+	StatusSince(service string, since time.Time) ([]StatusRecord, error)
+	DowntimeSince(service string, since time.Time) ([]DowntimeRecord, error)
+	Services() ([]string, error)
+	Close() error
+}
+
+// Bucket is a downsampled aggregate of the checks that fell within
+// [Start, Start+interval).
+type Bucket struct {
+	Start         time.Time `json:"start"`
+	UptimePct     float64   `json:"uptimePct"`
+	AvgResponse   float64   `json:"avgResponseTime"`
+	MinResponse   float64   `json:"minResponseTime"`
+	MaxResponse   float64   `json:"maxResponseTime"`
+	DowntimeCount int       `json:"downtimeCount"`
+	Checks        int       `json:"checks"`
+}
+
+// Downsample groups records into fixed-size buckets of the given interval
+// spanning [since, until), aggregating uptime percentage and response time
+// stats per bucket. Downtime records falling inside a bucket are counted
+// against it.
+func Downsample(records []StatusRecord, downtimes []DowntimeRecord, since, until time.Time, interval time.Duration) []Bucket {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	if !until.After(since) {
+		return nil
+	}
+
+	span := until.Sub(since)
+	numBuckets := int(span / interval)
+	if span%interval != 0 {
+		numBuckets++
+	}
+	buckets := make([]Bucket, numBuckets)
+	for i := range buckets {
+		buckets[i].Start = since.Add(time.Duration(i) * interval)
+		buckets[i].MinResponse = -1
+	}
+
+	bucketIndex := func(t time.Time) int {
+		if t.Before(since) || !t.Before(until) {
+			return -1
+		}
+		return int(t.Sub(since) / interval)
+	}
+
+	upCount := make([]int, numBuckets)
+	for _, r := range records {
+		i := bucketIndex(r.Timestamp)
+		if i < 0 {
+			continue
+		}
+		b := &buckets[i]
+		b.Checks++
+		if r.IsUp {
+			upCount[i]++
+		}
+		if b.MinResponse < 0 || r.ResponseTime < b.MinResponse {
+			b.MinResponse = r.ResponseTime
+		}
+		if r.ResponseTime > b.MaxResponse {
+			b.MaxResponse = r.ResponseTime
+		}
+		b.AvgResponse += r.ResponseTime
+	}
+
+	for _, d := range downtimes {
+		if i := bucketIndex(d.Timestamp); i >= 0 {
+			buckets[i].DowntimeCount++
+		}
+	}
+
+	for i := range buckets {
+		b := &buckets[i]
+		if b.Checks > 0 {
+			b.AvgResponse /= float64(b.Checks)
+			b.UptimePct = 100 * float64(upCount[i]) / float64(b.Checks)
+		} else {
+			b.MinResponse = 0
+		}
+	}
+
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].Start.Before(buckets[j].Start) })
+	return buckets
+}